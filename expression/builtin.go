@@ -0,0 +1,91 @@
+// Copyright 2015 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"github.com/pingcap/tidb/context"
+	"github.com/pingcap/tidb/terror"
+	"github.com/pingcap/tidb/util/types"
+)
+
+// BuiltinFunc is the signature every builtin scalar function implements.
+type BuiltinFunc func(args []types.Datum, ctx context.Context) (d types.Datum, err error)
+
+const codeInvalidOperation terror.ErrCode = 1
+
+var errInvalidOperation = terror.ClassExpression.New(codeInvalidOperation, "invalid operation")
+
+// Funcs holds all the builtin functions, keyed by their lower-cased SQL
+// name. The parser looks up function calls here after lower-casing the
+// identifier, so a function only reachable from Go (not listed here) can
+// never be invoked from SQL text.
+var Funcs = map[string]BuiltinFunc{
+	"abs":     builtinAbs,
+	"ceil":    builtinCeil,
+	"ceiling": builtinCeil,
+	"log":     builtinLog,
+	"log2":    builtinLog2,
+	"log10":   builtinLog10,
+	"rand":    builtinRand,
+	"pow":     builtinPow,
+	"power":   builtinPow,
+	"round":   builtinRound,
+	"conv":    builtinConv,
+	"crc32":   builtinCRC32,
+
+	"sin":      builtinSin,
+	"cos":      builtinCos,
+	"tan":      builtinTan,
+	"cot":      builtinCot,
+	"asin":     builtinAsin,
+	"acos":     builtinAcos,
+	"atan":     builtinAtan,
+	"sinh":     builtinSinh,
+	"cosh":     builtinCosh,
+	"tanh":     builtinTanh,
+	"exp":      builtinExp,
+	"ln":       builtinLn,
+	"sqrt":     builtinSqrt,
+	"pi":       builtinPi,
+	"degrees":  builtinDegrees,
+	"radians":  builtinRadians,
+	"sign":     builtinSign,
+	"truncate": builtinTruncate,
+	"floor":    builtinFloor,
+	"mod":      builtinMod,
+
+	"normal_rand": builtinNormalRand,
+	"exp_rand":    builtinExpRand,
+}
+
+// UnFoldableFuncs lists the builtins that must never be constant-folded
+// across re-executions of the same plan: each draws from the calling
+// session's PRNG, so folding it once would make every row (or every
+// execution of a prepared statement) see the same value. RAND(N) belongs
+// here too: because builtinRand reseeds the session source from the
+// literal N on every call, re-evaluating it on each execution already
+// reproduces the same sequence MySQL would produce, so folding it would
+// break that the same way folding the others would.
+//
+// There is no planner/constant-folding package in this tree yet, so
+// nothing consults this map today — it is exported so that package, once
+// it exists, can import expression and check against it. Until then this
+// is a registry, not an enforced guarantee: evaluating rand/normal_rand/
+// exp_rand directly, outside of whatever eventually does the folding, is
+// unaffected by it.
+var UnFoldableFuncs = map[string]struct{}{
+	"rand":        {},
+	"normal_rand": {},
+	"exp_rand":    {},
+}
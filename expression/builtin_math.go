@@ -20,12 +20,15 @@ package expression
 import (
 	"hash/crc32"
 	"math"
+	"math/big"
 	"math/rand"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/pingcap/tidb/context"
+	"github.com/pingcap/tidb/mysql"
 	"github.com/pingcap/tidb/parser/opcode"
 	"github.com/pingcap/tidb/util/types"
 )
@@ -141,16 +144,379 @@ func builtinLog10(args []types.Datum, ctx context.Context) (d types.Datum, err e
 
 }
 
+// See http://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_sin
+func builtinSin(args []types.Datum, ctx context.Context) (d types.Datum, err error) {
+	if args[0].IsNull() {
+		return d, nil
+	}
+	x, err := args[0].ToFloat64(ctx.GetSessionVars().StmtCtx)
+	if err != nil {
+		return d, errors.Trace(err)
+	}
+	d.SetFloat64(math.Sin(x))
+	return d, nil
+}
+
+// See http://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_cos
+func builtinCos(args []types.Datum, ctx context.Context) (d types.Datum, err error) {
+	if args[0].IsNull() {
+		return d, nil
+	}
+	x, err := args[0].ToFloat64(ctx.GetSessionVars().StmtCtx)
+	if err != nil {
+		return d, errors.Trace(err)
+	}
+	d.SetFloat64(math.Cos(x))
+	return d, nil
+}
+
+// See http://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_tan
+func builtinTan(args []types.Datum, ctx context.Context) (d types.Datum, err error) {
+	if args[0].IsNull() {
+		return d, nil
+	}
+	x, err := args[0].ToFloat64(ctx.GetSessionVars().StmtCtx)
+	if err != nil {
+		return d, errors.Trace(err)
+	}
+	d.SetFloat64(math.Tan(x))
+	return d, nil
+}
+
+// See http://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_cot
+func builtinCot(args []types.Datum, ctx context.Context) (d types.Datum, err error) {
+	if args[0].IsNull() {
+		return d, nil
+	}
+	x, err := args[0].ToFloat64(ctx.GetSessionVars().StmtCtx)
+	if err != nil {
+		return d, errors.Trace(err)
+	}
+	t := math.Tan(x)
+	if t == 0 {
+		return d, errors.Trace(types.ErrOverflow)
+	}
+	d.SetFloat64(1 / t)
+	return d, nil
+}
+
+// See http://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_asin
+func builtinAsin(args []types.Datum, ctx context.Context) (d types.Datum, err error) {
+	if args[0].IsNull() {
+		return d, nil
+	}
+	x, err := args[0].ToFloat64(ctx.GetSessionVars().StmtCtx)
+	if err != nil {
+		return d, errors.Trace(err)
+	}
+	if x < -1 || x > 1 {
+		return d, nil
+	}
+	d.SetFloat64(math.Asin(x))
+	return d, nil
+}
+
+// See http://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_acos
+func builtinAcos(args []types.Datum, ctx context.Context) (d types.Datum, err error) {
+	if args[0].IsNull() {
+		return d, nil
+	}
+	x, err := args[0].ToFloat64(ctx.GetSessionVars().StmtCtx)
+	if err != nil {
+		return d, errors.Trace(err)
+	}
+	if x < -1 || x > 1 {
+		return d, nil
+	}
+	d.SetFloat64(math.Acos(x))
+	return d, nil
+}
+
+// See http://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_atan
+func builtinAtan(args []types.Datum, ctx context.Context) (d types.Datum, err error) {
+	sc := ctx.GetSessionVars().StmtCtx
+
+	switch len(args) {
+	case 1:
+		if args[0].IsNull() {
+			return d, nil
+		}
+		x, err := args[0].ToFloat64(sc)
+		if err != nil {
+			return d, errors.Trace(err)
+		}
+		d.SetFloat64(math.Atan(x))
+		return d, nil
+	case 2:
+		if args[0].IsNull() || args[1].IsNull() {
+			return d, nil
+		}
+		y, err := args[0].ToFloat64(sc)
+		if err != nil {
+			return d, errors.Trace(err)
+		}
+		x, err := args[1].ToFloat64(sc)
+		if err != nil {
+			return d, errors.Trace(err)
+		}
+		d.SetFloat64(math.Atan2(y, x))
+		return d, nil
+	}
+	return d, nil
+}
+
+// See http://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_sinh
+func builtinSinh(args []types.Datum, ctx context.Context) (d types.Datum, err error) {
+	if args[0].IsNull() {
+		return d, nil
+	}
+	x, err := args[0].ToFloat64(ctx.GetSessionVars().StmtCtx)
+	if err != nil {
+		return d, errors.Trace(err)
+	}
+	d.SetFloat64(math.Sinh(x))
+	return d, nil
+}
+
+// See http://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_cosh
+func builtinCosh(args []types.Datum, ctx context.Context) (d types.Datum, err error) {
+	if args[0].IsNull() {
+		return d, nil
+	}
+	x, err := args[0].ToFloat64(ctx.GetSessionVars().StmtCtx)
+	if err != nil {
+		return d, errors.Trace(err)
+	}
+	d.SetFloat64(math.Cosh(x))
+	return d, nil
+}
+
+// See http://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_tanh
+func builtinTanh(args []types.Datum, ctx context.Context) (d types.Datum, err error) {
+	if args[0].IsNull() {
+		return d, nil
+	}
+	x, err := args[0].ToFloat64(ctx.GetSessionVars().StmtCtx)
+	if err != nil {
+		return d, errors.Trace(err)
+	}
+	d.SetFloat64(math.Tanh(x))
+	return d, nil
+}
+
+// See http://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_exp
+func builtinExp(args []types.Datum, ctx context.Context) (d types.Datum, err error) {
+	if args[0].IsNull() {
+		return d, nil
+	}
+	x, err := args[0].ToFloat64(ctx.GetSessionVars().StmtCtx)
+	if err != nil {
+		return d, errors.Trace(err)
+	}
+	d.SetFloat64(math.Exp(x))
+	return d, nil
+}
+
+// See http://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_ln
+func builtinLn(args []types.Datum, ctx context.Context) (d types.Datum, err error) {
+	if args[0].IsNull() {
+		return d, nil
+	}
+	x, err := args[0].ToFloat64(ctx.GetSessionVars().StmtCtx)
+	if err != nil {
+		return d, errors.Trace(err)
+	}
+	if x <= 0 {
+		return d, nil
+	}
+	d.SetFloat64(math.Log(x))
+	return d, nil
+}
+
+// See http://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_sqrt
+func builtinSqrt(args []types.Datum, ctx context.Context) (d types.Datum, err error) {
+	if args[0].IsNull() {
+		return d, nil
+	}
+	x, err := args[0].ToFloat64(ctx.GetSessionVars().StmtCtx)
+	if err != nil {
+		return d, errors.Trace(err)
+	}
+	if x < 0 {
+		return d, nil
+	}
+	d.SetFloat64(math.Sqrt(x))
+	return d, nil
+}
+
+// See http://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_pi
+func builtinPi(args []types.Datum, ctx context.Context) (d types.Datum, err error) {
+	d.SetFloat64(math.Pi)
+	return d, nil
+}
+
+// See http://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_degrees
+func builtinDegrees(args []types.Datum, ctx context.Context) (d types.Datum, err error) {
+	if args[0].IsNull() {
+		return d, nil
+	}
+	x, err := args[0].ToFloat64(ctx.GetSessionVars().StmtCtx)
+	if err != nil {
+		return d, errors.Trace(err)
+	}
+	d.SetFloat64(x * 180 / math.Pi)
+	return d, nil
+}
+
+// See http://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_radians
+func builtinRadians(args []types.Datum, ctx context.Context) (d types.Datum, err error) {
+	if args[0].IsNull() {
+		return d, nil
+	}
+	x, err := args[0].ToFloat64(ctx.GetSessionVars().StmtCtx)
+	if err != nil {
+		return d, errors.Trace(err)
+	}
+	d.SetFloat64(x * math.Pi / 180)
+	return d, nil
+}
+
+// See http://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_sign
+func builtinSign(args []types.Datum, ctx context.Context) (d types.Datum, err error) {
+	if args[0].IsNull() {
+		return d, nil
+	}
+	x, err := args[0].ToFloat64(ctx.GetSessionVars().StmtCtx)
+	if err != nil {
+		return d, errors.Trace(err)
+	}
+	switch {
+	case x > 0:
+		d.SetInt64(1)
+	case x < 0:
+		d.SetInt64(-1)
+	default:
+		d.SetInt64(0)
+	}
+	return d, nil
+}
+
+// See http://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_truncate
+func builtinTruncate(args []types.Datum, ctx context.Context) (d types.Datum, err error) {
+	if args[0].IsNull() {
+		return d, nil
+	}
+	sc := ctx.GetSessionVars().StmtCtx
+	x, err := args[0].ToFloat64(sc)
+	if err != nil {
+		return d, errors.Trace(err)
+	}
+
+	dec := 0
+	if len(args) == 2 {
+		y, err1 := args[1].ToInt64(sc)
+		if err1 != nil {
+			return d, errors.Trace(err1)
+		}
+		dec = int(y)
+	}
+
+	shift := math.Pow(10, float64(dec))
+	d.SetFloat64(math.Trunc(x*shift) / shift)
+	return d, nil
+}
+
+// See http://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_floor
+func builtinFloor(args []types.Datum, ctx context.Context) (d types.Datum, err error) {
+	if args[0].IsNull() ||
+		args[0].Kind() == types.KindUint64 || args[0].Kind() == types.KindInt64 {
+		return args[0], nil
+	}
+
+	f, err := args[0].ToFloat64(ctx.GetSessionVars().StmtCtx)
+	if err != nil {
+		return d, errors.Trace(err)
+	}
+	d.SetFloat64(math.Floor(f))
+	return d, nil
+}
+
+// builtinMod is the function form of the % operator.
+// See http://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_mod
+func builtinMod(args []types.Datum, ctx context.Context) (d types.Datum, err error) {
+	return arithmeticFuncFactory(opcode.Mod)(args, ctx)
+}
+
 // See http://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_rand
 func builtinRand(args []types.Datum, ctx context.Context) (d types.Datum, err error) {
+	sessionRand := getSessionRand(ctx)
 	if len(args) == 1 && !args[0].IsNull() {
 		seed, err := args[0].ToInt64(ctx.GetSessionVars().StmtCtx)
 		if err != nil {
 			return d, errors.Trace(err)
 		}
-		rand.Seed(seed)
+		sessionRand.Seed(seed)
 	}
-	d.SetFloat64(rand.Float64())
+	d.SetFloat64(sessionRand.Float64())
+	return d, nil
+}
+
+// getSessionRand returns the *rand.Rand that belongs to the current session,
+// creating and seeding it non-deterministically on first use. Unlike the
+// package-level math/rand functions, the source is private to the session,
+// so seeding RAND(N) in one connection never affects the sequence another
+// connection observes.
+func getSessionRand(ctx context.Context) *rand.Rand {
+	sessionVars := ctx.GetSessionVars()
+	if sessionVars.Rand == nil {
+		sessionVars.Rand = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+	return sessionVars.Rand
+}
+
+// builtinNormalRand is a TiDB extension, not part of standard MySQL: it
+// draws from a Gaussian distribution with the given mean and standard
+// deviation, using the same per-session PRNG as RAND() so that seeding one
+// is coherent with seeding the others. Useful for populating test tables
+// and benchmark fixtures directly from SQL. Listed in UnFoldableFuncs,
+// though nothing in this tree consults that registry yet.
+func builtinNormalRand(args []types.Datum, ctx context.Context) (d types.Datum, err error) {
+	if args[0].IsNull() || args[1].IsNull() {
+		return d, nil
+	}
+	sc := ctx.GetSessionVars().StmtCtx
+	mean, err := args[0].ToFloat64(sc)
+	if err != nil {
+		return d, errors.Trace(err)
+	}
+	stddev, err := args[1].ToFloat64(sc)
+	if err != nil {
+		return d, errors.Trace(err)
+	}
+	if stddev < 0 {
+		return d, nil
+	}
+	d.SetFloat64(mean + stddev*getSessionRand(ctx).NormFloat64())
+	return d, nil
+}
+
+// builtinExpRand is a TiDB extension, not part of standard MySQL: it draws
+// from an exponential distribution with the given rate, using the same
+// per-session PRNG as RAND(). Useful for populating test tables and
+// benchmark fixtures directly from SQL. Listed in UnFoldableFuncs, though
+// nothing in this tree consults that registry yet.
+func builtinExpRand(args []types.Datum, ctx context.Context) (d types.Datum, err error) {
+	if args[0].IsNull() {
+		return d, nil
+	}
+	lambda, err := args[0].ToFloat64(ctx.GetSessionVars().StmtCtx)
+	if err != nil {
+		return d, errors.Trace(err)
+	}
+	if lambda <= 0 {
+		return d, nil
+	}
+	d.SetFloat64(getSessionRand(ctx).ExpFloat64() / lambda)
 	return d, nil
 }
 
@@ -307,6 +673,10 @@ func arithmeticFuncFactory(op opcode.Op) BuiltinFunc {
 			return
 		}
 
+		if needsBigArithmetic(op, a, b) {
+			return computeBigArithmetic(op, a, b)
+		}
+
 		switch op {
 		case opcode.Plus:
 			return types.ComputePlus(a, b)
@@ -325,3 +695,200 @@ func arithmeticFuncFactory(op opcode.Op) BuiltinFunc {
 		}
 	}
 }
+
+// needsBigArithmetic reports whether a and b should be routed through the
+// math/big code path below rather than types.ComputePlus/Minus/Mul/Div,
+// which narrow through int64/uint64/float64 and can overflow or lose
+// precision: either operand is a DECIMAL, or both are integers and the
+// result of a Plus/Minus/Mul would not fit in int64.
+//
+// This only ever sees the two evaluated Datums, so it cannot also trigger
+// on "the expression's inferred result type is DECIMAL with precision >
+// 18" as the original request additionally asked for: that needs access to
+// the expression's FieldType, which belongs to the type-inference code
+// that picks result field types, not to this per-call Datum dispatch. That
+// wiring isn't present in this package and is left for a follow-up there
+// rather than silently dropped.
+func needsBigArithmetic(op opcode.Op, a, b types.Datum) bool {
+	if a.Kind() == types.KindMysqlDecimal || b.Kind() == types.KindMysqlDecimal {
+		return true
+	}
+	switch op {
+	case opcode.Plus, opcode.Minus, opcode.Mul:
+	default:
+		return false
+	}
+	x, ok := bigIntFromDatum(a)
+	if !ok {
+		return false
+	}
+	y, ok := bigIntFromDatum(b)
+	if !ok {
+		return false
+	}
+	r := new(big.Int)
+	switch op {
+	case opcode.Plus:
+		r.Add(x, y)
+	case opcode.Minus:
+		r.Sub(x, y)
+	case opcode.Mul:
+		r.Mul(x, y)
+	}
+	return !r.IsInt64()
+}
+
+func bigIntFromDatum(d types.Datum) (*big.Int, bool) {
+	switch d.Kind() {
+	case types.KindInt64:
+		return big.NewInt(d.GetInt64()), true
+	case types.KindUint64:
+		return new(big.Int).SetUint64(d.GetUint64()), true
+	default:
+		return nil, false
+	}
+}
+
+// computeBigArithmetic performs Plus/Minus/Mul using math/big so that large
+// integers neither overflow nor lose precision the way routing through
+// int64/uint64 would, and defers to computeBigDecimal for DECIMAL operands
+// (including DECIMAL Div/Mod/IntDiv). needsBigArithmetic never routes a
+// plain-integer Div/Mod/IntDiv here: ComputeDiv/ComputeMod/ComputeIntDiv
+// already handle those without the overflow risk Plus/Minus/Mul have, so
+// there is no integer Div/Mod/IntDiv case below.
+func computeBigArithmetic(op opcode.Op, a, b types.Datum) (d types.Datum, err error) {
+	if a.Kind() == types.KindMysqlDecimal || b.Kind() == types.KindMysqlDecimal {
+		return computeBigDecimal(op, a, b)
+	}
+
+	x, ok := bigIntFromDatum(a)
+	if !ok {
+		return d, errors.Trace(types.ErrOverflow)
+	}
+	y, ok := bigIntFromDatum(b)
+	if !ok {
+		return d, errors.Trace(types.ErrOverflow)
+	}
+
+	r := new(big.Int)
+	switch op {
+	case opcode.Plus:
+		r.Add(x, y)
+	case opcode.Minus:
+		r.Sub(x, y)
+	case opcode.Mul:
+		r.Mul(x, y)
+	default:
+		return d, errInvalidOperation.Gen("invalid op %v in big arithmetic operation", op)
+	}
+	return narrowBigInt(r)
+}
+
+// narrowBigInt fits r back into the smallest datum kind that holds it
+// exactly: int64, then uint64. Anything wider than 64 bits has genuinely
+// overflowed BIGINT UNSIGNED, so it is reported as an error rather than
+// silently re-typed as a string.
+func narrowBigInt(r *big.Int) (d types.Datum, err error) {
+	if r.IsInt64() {
+		d.SetInt64(r.Int64())
+		return d, nil
+	}
+	if r.Sign() >= 0 && r.IsUint64() {
+		d.SetUint64(r.Uint64())
+		return d, nil
+	}
+	return d, errors.Trace(types.ErrOverflow)
+}
+
+// computeBigDecimal performs Plus/Minus/Mul/Div/Mod/IntDiv on operands where
+// at least one side is a DECIMAL, using big.Rat so the result is exact for
+// any finite decimal instead of going through float64.
+func computeBigDecimal(op opcode.Op, a, b types.Datum) (d types.Datum, err error) {
+	x, ok := bigRatFromDatum(a)
+	if !ok {
+		return d, errors.Trace(types.ErrOverflow)
+	}
+	y, ok := bigRatFromDatum(b)
+	if !ok {
+		return d, errors.Trace(types.ErrOverflow)
+	}
+
+	switch op {
+	case opcode.IntDiv, opcode.Mod:
+		if y.Sign() == 0 {
+			return d, nil
+		}
+		// Scale both operands to a common denominator so the truncating
+		// integer ops below see whole numbers.
+		xs := new(big.Int).Mul(x.Num(), y.Denom())
+		ys := new(big.Int).Mul(y.Num(), x.Denom())
+		if ys.Sign() == 0 {
+			return d, nil
+		}
+		if op == opcode.IntDiv {
+			return narrowBigInt(new(big.Int).Quo(xs, ys))
+		}
+		m := new(big.Int).Rem(xs, ys)
+		den := new(big.Int).Mul(x.Denom(), y.Denom())
+		r := new(big.Rat).SetFrac(m, den)
+		return decimalDatumFromRat(r)
+	}
+
+	r := new(big.Rat)
+	switch op {
+	case opcode.Plus:
+		r.Add(x, y)
+	case opcode.Minus:
+		r.Sub(x, y)
+	case opcode.Mul:
+		r.Mul(x, y)
+	case opcode.Div:
+		if y.Sign() == 0 {
+			return d, nil
+		}
+		r.Quo(x, y)
+	default:
+		return d, errInvalidOperation.Gen("invalid op %v in big arithmetic operation", op)
+	}
+	return decimalDatumFromRat(r)
+}
+
+// trimDecimalString strips the trailing zeroes (and a now-bare decimal
+// point) that big.Rat.FloatString's fixed precision leaves behind.
+func trimDecimalString(s string) string {
+	if !strings.Contains(s, ".") {
+		return s
+	}
+	s = strings.TrimRight(s, "0")
+	return strings.TrimSuffix(s, ".")
+}
+
+// decimalDatumFromRat builds a proper KindMysqlDecimal datum from r, the
+// inverse of bigRatFromDatum's ToString() fallback. Decimal arithmetic must
+// come back as a decimal-typed datum, not a bare KindString value, or
+// callers expecting a decimal field type from decimal+decimal arithmetic
+// see the wrong type.
+func decimalDatumFromRat(r *big.Rat) (d types.Datum, err error) {
+	dec, err := mysql.ParseDecimal(trimDecimalString(r.FloatString(30)))
+	if err != nil {
+		return d, errors.Trace(err)
+	}
+	d.SetMysqlDecimal(dec)
+	return d, nil
+}
+
+func bigRatFromDatum(d types.Datum) (*big.Rat, bool) {
+	switch d.Kind() {
+	case types.KindInt64:
+		return new(big.Rat).SetInt64(d.GetInt64()), true
+	case types.KindUint64:
+		return new(big.Rat).SetInt(new(big.Int).SetUint64(d.GetUint64())), true
+	default:
+		s, err := d.ToString()
+		if err != nil {
+			return nil, false
+		}
+		r, ok := new(big.Rat).SetString(s)
+		return r, ok
+	}
+}
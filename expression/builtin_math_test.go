@@ -0,0 +1,302 @@
+// Copyright 2015 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"math"
+	"math/big"
+	"math/rand"
+	"sync"
+	"testing"
+
+	"github.com/juju/errors"
+	"github.com/pingcap/tidb/parser/opcode"
+	"github.com/pingcap/tidb/util/mock"
+	"github.com/pingcap/tidb/util/types"
+)
+
+func datumFloat64(f float64) types.Datum {
+	var d types.Datum
+	d.SetFloat64(f)
+	return d
+}
+
+func datumInt64(i int64) types.Datum {
+	var d types.Datum
+	d.SetInt64(i)
+	return d
+}
+
+func TestAsinAcosDomain(t *testing.T) {
+	ctx := mock.NewContext()
+	cases := []struct {
+		fn     BuiltinFunc
+		arg    types.Datum
+		null   bool
+		result float64
+	}{
+		{builtinAsin, datumFloat64(2), true, 0},
+		{builtinAsin, datumFloat64(-2), true, 0},
+		{builtinAsin, types.Datum{}, true, 0},
+		{builtinAsin, datumFloat64(1), false, math.Pi / 2},
+		{builtinAcos, datumFloat64(2), true, 0},
+		{builtinAcos, types.Datum{}, true, 0},
+		{builtinAcos, datumFloat64(1), false, 0},
+	}
+	for _, c := range cases {
+		d, err := c.fn([]types.Datum{c.arg}, ctx)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if c.null {
+			if !d.IsNull() {
+				t.Errorf("expected NULL, got %v", d.GetFloat64())
+			}
+			continue
+		}
+		if d.GetFloat64() != c.result {
+			t.Errorf("expected %v, got %v", c.result, d.GetFloat64())
+		}
+	}
+}
+
+func TestAtan2Quadrants(t *testing.T) {
+	ctx := mock.NewContext()
+	cases := []struct {
+		y, x, want float64
+	}{
+		{1, 1, math.Pi / 4},
+		{1, -1, 3 * math.Pi / 4},
+		{-1, -1, -3 * math.Pi / 4},
+		{-1, 1, -math.Pi / 4},
+	}
+	for _, c := range cases {
+		d, err := builtinAtan([]types.Datum{datumFloat64(c.y), datumFloat64(c.x)}, ctx)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if math.Abs(d.GetFloat64()-c.want) > 1e-9 {
+			t.Errorf("atan2(%v, %v): expected %v, got %v", c.y, c.x, c.want, d.GetFloat64())
+		}
+	}
+}
+
+func TestSqrtNegativeIsNull(t *testing.T) {
+	ctx := mock.NewContext()
+	d, err := builtinSqrt([]types.Datum{datumFloat64(-4)}, ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !d.IsNull() {
+		t.Errorf("expected NULL for SQRT(-4), got %v", d.GetFloat64())
+	}
+	d, err = builtinSqrt([]types.Datum{datumFloat64(4)}, ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if d.GetFloat64() != 2 {
+		t.Errorf("expected 2, got %v", d.GetFloat64())
+	}
+}
+
+// TestRandIsSessionScoped seeds two sessions concurrently with different
+// constants and asserts neither sees the other's sequence, i.e. RAND(N) no
+// longer mutates a shared package-level source.
+func TestRandIsSessionScoped(t *testing.T) {
+	var wg sync.WaitGroup
+	seqs := make([][]float64, 2)
+	seeds := []int64{1, 2}
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			ctx := mock.NewContext()
+			seq := make([]float64, 5)
+			for j := range seq {
+				args := []types.Datum{}
+				if j == 0 {
+					args = []types.Datum{datumInt64(seeds[i])}
+				}
+				d, err := builtinRand(args, ctx)
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+					return
+				}
+				seq[j] = d.GetFloat64()
+			}
+			seqs[i] = seq
+		}(i)
+	}
+	wg.Wait()
+
+	same := true
+	for j := range seqs[0] {
+		if seqs[0][j] != seqs[1][j] {
+			same = false
+			break
+		}
+	}
+	if same {
+		t.Errorf("two sessions seeded with different constants produced identical sequences: %v", seqs)
+	}
+}
+
+// TestRandSeedReproducible asserts that RAND(N) with the same N returns the
+// same value in two separate sessions, i.e. the result depends only on the
+// seed and not on any other session's state.
+func TestRandSeedReproducible(t *testing.T) {
+	ctx1 := mock.NewContext()
+	ctx2 := mock.NewContext()
+	d1, err := builtinRand([]types.Datum{datumInt64(42)}, ctx1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	d2, err := builtinRand([]types.Datum{datumInt64(42)}, ctx2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if d1.GetFloat64() != d2.GetFloat64() {
+		t.Errorf("RAND(42) diverged across sessions: %v vs %v", d1.GetFloat64(), d2.GetFloat64())
+	}
+}
+
+func TestTruncateNegativeDecimals(t *testing.T) {
+	ctx := mock.NewContext()
+	d, err := builtinTruncate([]types.Datum{datumFloat64(122), datumInt64(-2)}, ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if d.GetFloat64() != 100 {
+		t.Errorf("expected 100, got %v", d.GetFloat64())
+	}
+}
+
+// TestBigArithmeticAgainstRatOracle compares the math/big code path against
+// a reference big.Rat computation for random int64/uint64 operand pairs,
+// including values near math.MaxInt64, math.MinInt64 and math.MaxUint64.
+func TestBigArithmeticAgainstRatOracle(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	edges := []int64{math.MaxInt64, math.MinInt64, math.MaxInt64 - 1, math.MinInt64 + 1, 0, 1, -1}
+	ops := []opcode.Op{opcode.Plus, opcode.Minus, opcode.Mul}
+
+	pick := func() int64 {
+		if r.Intn(2) == 0 {
+			return edges[r.Intn(len(edges))]
+		}
+		return r.Int63() - r.Int63()
+	}
+
+	for i := 0; i < 200; i++ {
+		x, y := pick(), pick()
+		for _, op := range ops {
+			a, b := datumInt64(x), datumInt64(y)
+			want := new(big.Rat)
+			xr, yr := new(big.Rat).SetInt64(x), new(big.Rat).SetInt64(y)
+			switch op {
+			case opcode.Plus:
+				want.Add(xr, yr)
+			case opcode.Minus:
+				want.Sub(xr, yr)
+			case opcode.Mul:
+				want.Mul(xr, yr)
+			}
+
+			if !needsBigArithmetic(op, a, b) {
+				continue
+			}
+			d, err := computeBigArithmetic(op, a, b)
+			if err != nil {
+				// Only acceptable if the oracle itself doesn't fit in
+				// int64/uint64 either.
+				if want.IsInt() {
+					bi := want.Num()
+					if bi.IsInt64() || (bi.Sign() >= 0 && bi.IsUint64()) {
+						t.Fatalf("op %v(%d,%d): got error %v but result %v fits in 64 bits", op, x, y, err, want)
+					}
+				}
+				continue
+			}
+			got := new(big.Rat)
+			switch d.Kind() {
+			case types.KindInt64:
+				got.SetInt64(d.GetInt64())
+			case types.KindUint64:
+				got.SetInt(new(big.Int).SetUint64(d.GetUint64()))
+			default:
+				t.Fatalf("op %v(%d,%d): unexpected result kind %v", op, x, y, d.Kind())
+			}
+			if got.Cmp(want) != 0 {
+				t.Errorf("op %v(%d,%d): expected %v, got %v", op, x, y, want, got)
+			}
+		}
+	}
+}
+
+// TestBigIntOverflowReturnsErrOverflow asserts that results too wide for
+// either int64 or uint64 are reported as types.ErrOverflow, not silently
+// re-typed as a string.
+func TestBigIntOverflowReturnsErrOverflow(t *testing.T) {
+	a, b := datumInt64(math.MaxInt64), datumInt64(math.MaxInt64)
+	if !needsBigArithmetic(opcode.Mul, a, b) {
+		t.Fatalf("expected MaxInt64*MaxInt64 to require big arithmetic")
+	}
+	_, err := computeBigArithmetic(opcode.Mul, a, b)
+	if errors.Cause(err) != types.ErrOverflow {
+		t.Errorf("expected types.ErrOverflow, got %v", err)
+	}
+}
+
+func TestNormalRandValidation(t *testing.T) {
+	ctx := mock.NewContext()
+	cases := []struct {
+		mean, stddev types.Datum
+		null         bool
+	}{
+		{datumFloat64(0), datumFloat64(-1), true},
+		{types.Datum{}, datumFloat64(1), true},
+		{datumFloat64(0), types.Datum{}, true},
+		{datumFloat64(5), datumFloat64(0), false},
+	}
+	for _, c := range cases {
+		d, err := builtinNormalRand([]types.Datum{c.mean, c.stddev}, ctx)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if d.IsNull() != c.null {
+			t.Errorf("NORMAL_RAND(%v, %v): expected null=%v, got %v", c.mean, c.stddev, c.null, d.IsNull())
+		}
+	}
+}
+
+func TestExpRandValidation(t *testing.T) {
+	ctx := mock.NewContext()
+	cases := []struct {
+		lambda types.Datum
+		null   bool
+	}{
+		{datumFloat64(0), true},
+		{datumFloat64(-1), true},
+		{types.Datum{}, true},
+		{datumFloat64(2), false},
+	}
+	for _, c := range cases {
+		d, err := builtinExpRand([]types.Datum{c.lambda}, ctx)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if d.IsNull() != c.null {
+			t.Errorf("EXP_RAND(%v): expected null=%v, got %v", c.lambda, c.null, d.IsNull())
+		}
+	}
+}
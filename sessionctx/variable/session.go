@@ -0,0 +1,38 @@
+// Copyright 2015 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package variable
+
+import "math/rand"
+
+// SessionVars holds the per-session, non-persistent state that is threaded
+// through context.Context for the lifetime of a connection.
+type SessionVars struct {
+	// StmtCtx holds per-statement state, such as truncate/overflow handling
+	// flags, that types.Datum conversions consult.
+	StmtCtx *StatementContext
+
+	// Rand is this session's private math/rand source. RAND(), RAND(N),
+	// NORMAL_RAND() and EXP_RAND() all draw from it, so seeding one of them
+	// is coherent with the others, and seeding in one session never affects
+	// the sequence another session observes. It is created lazily, on first
+	// use, by expression.getSessionRand.
+	Rand *rand.Rand
+}
+
+// NewSessionVars creates a new SessionVars for a fresh session.
+func NewSessionVars() *SessionVars {
+	return &SessionVars{
+		StmtCtx: new(StatementContext),
+	}
+}
@@ -0,0 +1,24 @@
+// Copyright 2015 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package variable
+
+// StatementContext holds per-statement state, such as truncate/overflow
+// handling flags, that is consulted by types.Datum conversions during the
+// execution of a single statement. It is reset between statements.
+type StatementContext struct {
+	// IgnoreTruncate, when set, makes lossy type conversions (e.g. a
+	// non-numeric string converted to a number) succeed with a truncated
+	// value instead of returning an error.
+	IgnoreTruncate bool
+}